@@ -0,0 +1,44 @@
+// Copyright 2015 Red Hat Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package man
+
+import (
+	"io"
+	"os"
+)
+
+// createAtomic writes the content produced by write to filename without
+// ever leaving a partially-written file in its place: the content is
+// written to filename+".tmp" first and only renamed over filename once
+// write has returned successfully. A failure partway through (a template
+// error, a full disk) leaves any previous version of filename untouched.
+func createAtomic(filename string, write func(io.Writer) error) error {
+	tmpname := filename + ".tmp"
+	f, err := os.Create(tmpname)
+	if err != nil {
+		return err
+	}
+
+	if err := write(f); err != nil {
+		f.Close()
+		os.Remove(tmpname)
+		return err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(tmpname)
+		return err
+	}
+
+	return os.Rename(tmpname, filename)
+}
@@ -0,0 +1,110 @@
+// Copyright 2015 Red Hat Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package man
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// GenerateCompletionOptions is used to configure how GenerateCompletions
+// will do its job.
+type GenerateCompletionOptions struct {
+	// Directory location for where to generate the completion scripts.
+	Directory string
+
+	// IncludeDesc controls whether the bash, zsh, fish and PowerShell
+	// completions include flag/command descriptions.
+	IncludeDesc bool
+}
+
+// GenerateCompletions walks cmd and all of its children once, registering
+// flag value completions declared through the "man-arg-hints" annotation
+// (the same one the man/Markdown/reST flag renderers read), then writes
+// bash, zsh, fish and PowerShell completion scripts for the whole command
+// tree into opts.Directory. Call it alongside GenerateManPages so a single
+// build step produces both the man pages and the completions users expect
+// to find next to them.
+func GenerateCompletions(cmd *cobra.Command, opts *GenerateCompletionOptions) error {
+	registerArgHintCompletions(cmd)
+
+	root := cmd.Root()
+	name := root.Name()
+	if name == "" {
+		return fmt.Errorf("you need a command name to generate completions")
+	}
+
+	if err := root.GenBashCompletionFileV2(filepath.Join(opts.Directory, name+".bash"), opts.IncludeDesc); err != nil {
+		return err
+	}
+	if opts.IncludeDesc {
+		if err := root.GenZshCompletionFile(filepath.Join(opts.Directory, name+".zsh")); err != nil {
+			return err
+		}
+	} else {
+		if err := root.GenZshCompletionFileNoDesc(filepath.Join(opts.Directory, name+".zsh")); err != nil {
+			return err
+		}
+	}
+	if err := root.GenFishCompletionFile(filepath.Join(opts.Directory, name+".fish"), opts.IncludeDesc); err != nil {
+		return err
+	}
+	if opts.IncludeDesc {
+		if err := root.GenPowerShellCompletionFileWithDesc(filepath.Join(opts.Directory, name+".ps1")); err != nil {
+			return err
+		}
+	} else {
+		if err := root.GenPowerShellCompletionFile(filepath.Join(opts.Directory, name+".ps1")); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// registerArgHintCompletions walks cmd and its children, translating each
+// flag's "man-arg-hints" annotation into a shell completion registration:
+// a hint of "file" or "file:<ext>,<ext>" completes filenames (optionally
+// restricted to the given extensions), and a pipe-separated hint (e.g.
+// "json|yaml|toml") completes that fixed list of choices. Flags without a
+// recognized hint are left to cobra's default completion.
+func registerArgHintCompletions(cmd *cobra.Command) {
+	for _, c := range cmd.Commands() {
+		registerArgHintCompletions(c)
+	}
+
+	cmd.Flags().VisitAll(func(flag *pflag.Flag) {
+		hintArr, exists := flag.Annotations["man-arg-hints"]
+		if !exists || len(hintArr) == 0 {
+			return
+		}
+
+		hint := hintArr[0]
+		switch {
+		case strings.EqualFold(hint, "file"):
+			_ = cmd.MarkFlagFilename(flag.Name)
+		case strings.HasPrefix(strings.ToLower(hint), "file:"):
+			exts := strings.Split(hint[len("file:"):], ",")
+			_ = cmd.MarkFlagFilename(flag.Name, exts...)
+		case strings.Contains(hint, "|"):
+			choices := strings.Split(hint, "|")
+			_ = cmd.RegisterFlagCompletionFunc(flag.Name, func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+				return choices, cobra.ShellCompDirectiveNoFileComp
+			})
+		}
+	})
+}
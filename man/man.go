@@ -21,8 +21,8 @@ import (
 	"reflect"
 	"runtime"
 	"sort"
+	"strconv"
 	"strings"
-	"text/template"
 	"time"
 
 	"github.com/spf13/cobra"
@@ -43,6 +43,12 @@ type GenerateManOptions struct {
 	// Will default to Now
 	Date *time.Time
 
+	// SourceDateEpoch overrides Date with a Unix timestamp, for
+	// reproducible builds. When nil, the SOURCE_DATE_EPOCH environment
+	// variable (https://reproducible-builds.org/specs/source-date-epoch/)
+	// is used if set. Date, if set, always takes precedence over both.
+	SourceDateEpoch *int64
+
 	// LeftFooter used across all pages
 	LeftFooter string
 
@@ -81,8 +87,41 @@ type GenerateManOptions struct {
 	CommandSeparator string
 
 	// UseTemplate allows you to override the default go template used to
-	// generate the man pages with your own version.
+	// generate the man pages with your own version. Only used by the
+	// default troff renderer; ignored when Renderer is set.
 	UseTemplate string
+
+	// Renderer controls which man page dialect is produced. When nil the
+	// built-in troff/man(7) renderer is used. Set it to &MdocRenderer{}
+	// to generate BSD mdoc(7) pages instead, or to your own Renderer
+	// implementation.
+	Renderer Renderer
+
+	// CustomSections declares extra sections beyond the built-in
+	// FILES/BUGS/ENVIRONMENT/EXAMPLES ones. See CustomSection for how a
+	// command overrides one for itself.
+	CustomSections []CustomSection
+}
+
+// CustomSection declares one extra man page section (e.g. HISTORY, EXIT
+// STATUS, STANDARDS, SECURITY) that isn't one of the built-in
+// FILES/BUGS/ENVIRONMENT/EXAMPLES sections. Heading is the section title,
+// Annotation is the cmd.Annotations key a command uses to override the
+// body for itself (by convention "man-section-<key>"), and Default is
+// used for any command that doesn't set that annotation. A section with
+// an empty resulting body (no Default and no annotation override) is
+// omitted from the page entirely.
+type CustomSection struct {
+	Heading    string
+	Annotation string
+	Default    string
+}
+
+// CustomSectionValue is a CustomSection resolved for one specific command,
+// ready to be rendered by a template via Model.CustomSections.
+type CustomSectionValue struct {
+	Heading string
+	Body    string
 }
 
 // GenerateManPages - build man pages for the passed in cobra.Command
@@ -110,74 +149,95 @@ func GenerateManPages(cmd *cobra.Command, opts *GenerateManOptions) error {
 		return fmt.Errorf("you need a command name to have a man page")
 	}
 	filename := filepath.Join(opts.Directory, basename+"."+section)
-	f, err := os.Create(filename)
-	if err != nil {
-		return err
-	}
-	defer f.Close()
-
-	return generateManPage(cmd, opts, f)
+	return createAtomic(filename, func(f io.Writer) error {
+		m := BuildModel(cmd, opts)
+		if opts.Renderer != nil {
+			return opts.Renderer.Render(f, *m)
+		}
+		return RenderModel(m, f, opts.UseTemplate)
+	})
 }
 
-type manStruct struct {
-	Date             *time.Time
-	Section          string
-	CenterFooter     string
-	LeftFooter       string
-	CenterHeader     string
-	UseLine          string
-	CommandPath      string
-	ShortDescription string
-	Description      string
-	NoArgs           bool
-
-	AllFlags          []Flag
-	InheritedFlags    []Flag
-	NonInheritedFlags []Flag
-	SeeAlsos          []SeeAlso
-	SubCommands       []string
-
-	Author      string
-	Environment string
-	Files       string
-	Bugs        string
-	Examples    string
+// Model is the structured, format-agnostic representation of a single
+// cobra.Command extracted by BuildModel (or the Markdown/reST/YAML
+// generators' equivalents). Callers can transform a Model - filter hidden
+// subtrees, translate strings, merge in externally-authored prose - between
+// extraction and rendering, or marshal it to JSON/YAML for downstream
+// tooling.
+type Model struct {
+	Date             *time.Time `json:"date,omitempty" yaml:"date,omitempty"`
+	Section          string     `json:"section,omitempty" yaml:"section,omitempty"`
+	CenterFooter     string     `json:"center_footer,omitempty" yaml:"center_footer,omitempty"`
+	LeftFooter       string     `json:"left_footer,omitempty" yaml:"left_footer,omitempty"`
+	CenterHeader     string     `json:"center_header,omitempty" yaml:"center_header,omitempty"`
+	UseLine          string     `json:"use_line,omitempty" yaml:"use_line,omitempty"`
+	CommandPath      string     `json:"command_path" yaml:"command_path"`
+	ShortDescription string     `json:"short_description,omitempty" yaml:"short_description,omitempty"`
+	Description      string     `json:"description,omitempty" yaml:"description,omitempty"`
+	NoArgs           bool       `json:"no_args,omitempty" yaml:"no_args,omitempty"`
+
+	AllFlags          []Flag    `json:"all_flags,omitempty" yaml:"all_flags,omitempty"`
+	InheritedFlags    []Flag    `json:"inherited_flags,omitempty" yaml:"inherited_flags,omitempty"`
+	NonInheritedFlags []Flag    `json:"non_inherited_flags,omitempty" yaml:"non_inherited_flags,omitempty"`
+	SeeAlsos          []SeeAlso `json:"see_alsos,omitempty" yaml:"see_alsos,omitempty"`
+	SubCommands       []string  `json:"sub_commands,omitempty" yaml:"sub_commands,omitempty"`
+
+	Author         string               `json:"author,omitempty" yaml:"author,omitempty"`
+	Environment    string               `json:"environment,omitempty" yaml:"environment,omitempty"`
+	Files          string               `json:"files,omitempty" yaml:"files,omitempty"`
+	Bugs           string               `json:"bugs,omitempty" yaml:"bugs,omitempty"`
+	Examples       string               `json:"examples,omitempty" yaml:"examples,omitempty"`
+	CustomSections []CustomSectionValue `json:"custom_sections,omitempty" yaml:"custom_sections,omitempty"`
 }
 
 type Flag struct {
-	Shorthand   string
-	Name        string
-	NoOptDefVal string
-	DefValue    string
-	Usage       string
-	ArgHint     string
+	Shorthand   string `json:"shorthand,omitempty" yaml:"shorthand,omitempty"`
+	Name        string `json:"name" yaml:"name"`
+	NoOptDefVal string `json:"no_opt_def_val,omitempty" yaml:"no_opt_def_val,omitempty"`
+	DefValue    string `json:"default_value,omitempty" yaml:"default_value,omitempty"`
+	Usage       string `json:"usage,omitempty" yaml:"usage,omitempty"`
+	ArgHint     string `json:"arg_hint,omitempty" yaml:"arg_hint,omitempty"`
 }
 
 type SeeAlso struct {
-	CmdPath string
-	Section string
+	CmdPath string `json:"cmd_path" yaml:"cmd_path"`
+	Section string `json:"section,omitempty" yaml:"section,omitempty"`
 }
 
-func generateManPage(cmd *cobra.Command, opts *GenerateManOptions, w io.Writer) error {
-	values := manStruct{}
+// BuildModel extracts a Model from cmd: its description, flags, SEE ALSO
+// relationships, Files/Bugs/Environment/Examples/CustomSections, and the
+// troff page header fields (Date/CenterFooter/LeftFooter/CenterHeader).
+// Pass the result to RenderModel, to one of GenerateManOptions.Renderer's
+// implementations, or inspect/transform/serialize it directly.
+// GenerateManPages is a thin wrapper over BuildModel and RenderModel.
+func BuildModel(cmd *cobra.Command, opts *GenerateManOptions) *Model {
+	section := opts.Section
+	if section == "" {
+		section = "1"
+	}
+
+	values := newDocStruct(cmd, section, opts.Author, opts.Files, opts.Bugs, opts.Environment, opts.CustomSections)
 
 	// Header fields
 	values.LeftFooter = opts.LeftFooter
 	values.CenterHeader = opts.CenterHeader
-	values.Section = opts.Section
-	if values.Section == "" {
-		values.Section = "1"
-	}
-	values.Date = opts.Date
-	if opts.Date == nil {
-		now := time.Now()
-		values.Date = &now
-	}
+	values.Date = resolveDate(opts)
 	values.CenterFooter = opts.CenterFooter
-	if opts.CenterFooter == "" {
+	if values.CenterFooter == "" {
 		values.CenterFooter = values.Date.Format("Jan 2006")
 	}
 
+	return &values
+}
+
+// newDocStruct builds the format-agnostic parts of a Model: the
+// description, flags, SEE ALSO list and the Files/Bugs/Environment/Examples
+// sections (honoring the per-command annotation overrides). It is shared by
+// every doc generator (man, Markdown, reST, YAML) so they stay in sync as
+// the underlying cobra.Command tree is walked.
+func newDocStruct(cmd *cobra.Command, section, author, files, bugs, environment string, customSections []CustomSection) Model {
+	values := Model{}
+	values.Section = section
 	values.ShortDescription = cmd.Short
 	values.UseLine = cmd.UseLine()
 	values.CommandPath = cmd.CommandPath()
@@ -211,31 +271,31 @@ func generateManPage(cmd *cobra.Command, opts *GenerateManOptions, w io.Writer)
 
 	// ENVIRONMENT section
 	altEnvironmentSection, _ := cmd.Annotations["man-environment-section"]
-	if opts.Environment != "" || altEnvironmentSection != "" {
+	if environment != "" || altEnvironmentSection != "" {
 		if altEnvironmentSection != "" {
 			values.Environment = altEnvironmentSection
 		} else {
-			values.Environment = opts.Environment
+			values.Environment = environment
 		}
 	}
 
 	// FILES section
 	altFilesSection, _ := cmd.Annotations["man-files-section"]
-	if opts.Files != "" || altFilesSection != "" {
+	if files != "" || altFilesSection != "" {
 		if altFilesSection != "" {
 			values.Files = altFilesSection
 		} else {
-			values.Files = opts.Files
+			values.Files = files
 		}
 	}
 
 	// BUGS section
 	altBugsSection, _ := cmd.Annotations["man-bugs-section"]
-	if opts.Bugs != "" || altBugsSection != "" {
+	if bugs != "" || altBugsSection != "" {
 		if altBugsSection != "" {
 			values.Bugs = altBugsSection
 		} else {
-			values.Bugs = opts.Bugs
+			values.Bugs = bugs
 		}
 	}
 
@@ -250,32 +310,54 @@ func generateManPage(cmd *cobra.Command, opts *GenerateManOptions, w io.Writer)
 	}
 
 	// AUTHOR section
-	values.Author = opts.Author
+	values.Author = author
+
+	// CUSTOM sections
+	for _, cs := range customSections {
+		body := cs.Default
+		if alt, ok := cmd.Annotations[cs.Annotation]; ok && alt != "" {
+			body = alt
+		}
+		if body == "" {
+			continue
+		}
+		values.CustomSections = append(values.CustomSections, CustomSectionValue{
+			Heading: cs.Heading,
+			Body:    body,
+		})
+	}
 
 	// SEE ALSO section
-	values.SeeAlsos = generateSeeAlsos(cmd, values.Section)
+	values.SeeAlsos = generateSeeAlsos(cmd, section)
 
-	// Build the template and generate the man page
-	manTemplateStr := defaultManTemplate
-	if opts.UseTemplate != "" {
-		manTemplateStr = opts.UseTemplate
-	}
-	funcMap := template.FuncMap{
-		"upper":         strings.ToUpper,
-		"backslashify":  backslashify,
-		"dashify":       dashify,
-		"simpleToTroff": simpleToTroff,
-		"simpleToMdoc":  simpleToMdoc,
+	return values
+}
+
+// resolveDate picks the timestamp a man page's header should carry,
+// preferring an explicit opts.Date, then opts.SourceDateEpoch, then the
+// SOURCE_DATE_EPOCH environment variable, and finally time.Now(). The env
+// var lets packagers building .deb/.rpm pin every generated page to their
+// build's epoch without touching caller code.
+func resolveDate(opts *GenerateManOptions) *time.Time {
+	if opts.Date != nil {
+		return opts.Date
 	}
-	parsedTemplate, err := template.New("man").Funcs(funcMap).Parse(manTemplateStr)
-	if err != nil {
-		return err
+
+	epoch := opts.SourceDateEpoch
+	if epoch == nil {
+		if raw, ok := os.LookupEnv("SOURCE_DATE_EPOCH"); ok {
+			if parsed, err := strconv.ParseInt(raw, 10, 64); err == nil {
+				epoch = &parsed
+			}
+		}
 	}
-	err = parsedTemplate.Execute(w, values)
-	if err != nil {
-		return err
+	if epoch != nil {
+		t := time.Unix(*epoch, 0).UTC()
+		return &t
 	}
-	return nil
+
+	now := time.Now()
+	return &now
 }
 
 func genFlagArray(flags *pflag.FlagSet) []Flag {
@@ -300,17 +382,23 @@ func genFlagArray(flags *pflag.FlagSet) []Flag {
 		flagArray = append(flagArray, manFlag)
 	})
 
+	// Don't rely on pflag's VisitAll order being stable across versions;
+	// sort explicitly so the same flag set always renders identically.
+	sort.Slice(flagArray, func(i, j int) bool {
+		return flagArray[i].Name < flagArray[j].Name
+	})
+
 	return flagArray
 }
 
 func generateSeeAlsos(cmd *cobra.Command, section string) []SeeAlso {
+	var parent *SeeAlso
 	seealsos := make([]SeeAlso, 0)
 	if cmd.HasParent() {
-		see := SeeAlso{
+		parent = &SeeAlso{
 			CmdPath: cmd.Parent().CommandPath(),
 			Section: section,
 		}
-		seealsos = append(seealsos, see)
 		// TODO: may want to control if siblings are shown or not
 		siblings := cmd.Parent().Commands()
 		sort.Sort(byName(siblings))
@@ -318,11 +406,10 @@ func generateSeeAlsos(cmd *cobra.Command, section string) []SeeAlso {
 			if !c.IsAvailableCommand() || c.IsAdditionalHelpTopicCommand() || c.Name() == cmd.Name() {
 				continue
 			}
-			see := SeeAlso{
+			seealsos = append(seealsos, SeeAlso{
 				CmdPath: c.CommandPath(),
 				Section: section,
-			}
-			seealsos = append(seealsos, see)
+			})
 		}
 	}
 	children := cmd.Commands()
@@ -331,11 +418,16 @@ func generateSeeAlsos(cmd *cobra.Command, section string) []SeeAlso {
 		if !c.IsAvailableCommand() || c.IsAdditionalHelpTopicCommand() {
 			continue
 		}
-		see := SeeAlso{
+		seealsos = append(seealsos, SeeAlso{
 			CmdPath: c.CommandPath(),
 			Section: section,
-		}
-		seealsos = append(seealsos, see)
+		})
+	}
+
+	// The parent is always listed first: it's a "belongs to" reference,
+	// not a peer, so it shouldn't be folded into the alphabetical sort.
+	if parent != nil {
+		seealsos = append([]SeeAlso{*parent}, seealsos...)
 	}
 
 	return seealsos
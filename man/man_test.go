@@ -0,0 +1,143 @@
+// Copyright 2015 Red Hat Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package man
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// testSubCommand builds a small "app sub" tree and returns the sub
+// command, the one tests build a Model or page for.
+func testSubCommand() *cobra.Command {
+	root := &cobra.Command{
+		Use:   "app",
+		Short: "does app things",
+		Long:  "app does app things, including `sub` work.",
+		Run:   func(cmd *cobra.Command, args []string) {},
+	}
+	sub := &cobra.Command{
+		Use:   "sub",
+		Short: "does sub things",
+		Run:   func(cmd *cobra.Command, args []string) {},
+	}
+	sub.Flags().StringP("verbose", "v", "", "be verbose")
+	root.AddCommand(sub)
+	return sub
+}
+
+func testOpts() *GenerateManOptions {
+	date := time.Date(2020, time.January, 2, 0, 0, 0, 0, time.UTC)
+	return &GenerateManOptions{Date: &date}
+}
+
+func TestBuildModelRenderModelRoundTrip(t *testing.T) {
+	m := BuildModel(testSubCommand(), testOpts())
+
+	if m.CommandPath != "app sub" {
+		t.Fatalf("CommandPath = %q, want %q", m.CommandPath, "app sub")
+	}
+
+	var buf bytes.Buffer
+	if err := RenderModel(m, &buf, ""); err != nil {
+		t.Fatalf("RenderModel: %v", err)
+	}
+
+	out := buf.String()
+	if !strings.Contains(out, `.TH "APP-SUB"`) {
+		t.Errorf("output missing .TH header: %s", out)
+	}
+	if !strings.Contains(out, `\-v, \-\-verbose`) {
+		t.Errorf("output missing verbose flag entry: %s", out)
+	}
+}
+
+func TestMdocRendererFlagAlternatives(t *testing.T) {
+	m := BuildModel(testSubCommand(), testOpts())
+
+	var buf bytes.Buffer
+	if err := (&MdocRenderer{}).Render(&buf, *m); err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+
+	out := buf.String()
+	if !strings.Contains(out, ".Op Fl v | Fl verbose") {
+		t.Errorf("SYNOPSIS missing mdoc Fl alternation: %s", out)
+	}
+	if !strings.Contains(out, ".It Fl v , Fl verbose") {
+		t.Errorf("OPTIONS missing shorthand in .It entry: %s", out)
+	}
+}
+
+func TestSimpleToMdocSplitsInlineCode(t *testing.T) {
+	out := simpleToMdoc("Run `sub` to do the thing.")
+	lines := strings.Split(out, "\n")
+
+	want := []string{"Run", ".Sy sub", "to do the thing."}
+	if len(lines) != len(want) {
+		t.Fatalf("simpleToMdoc(...) = %q, want %d lines matching %q", lines, len(want), want)
+	}
+	for i, line := range lines {
+		if line != want[i] {
+			t.Errorf("line %d = %q, want %q", i, line, want[i])
+		}
+	}
+}
+
+func TestYAMLLinkHandlerDoesNotClobberCmdPath(t *testing.T) {
+	root := testSubCommand().Parent()
+	opts := &GenerateYAMLOptions{
+		Directory: t.TempDir(),
+		LinkHandler: func(cmdPath, ref string) string {
+			return "/docs/" + ref
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := generateYAMLPage(root, opts, &buf); err != nil {
+		t.Fatalf("generateYAMLPage: %v", err)
+	}
+
+	out := buf.String()
+	if !strings.Contains(out, "cmd_path: app sub") {
+		t.Errorf("see_also cmd_path was overwritten by LinkHandler: %s", out)
+	}
+	if !strings.Contains(out, "href: /docs/app-sub.yaml") {
+		t.Errorf("see_also href missing LinkHandler output: %s", out)
+	}
+}
+
+func TestMarkdownAndReSTRenderSeeAlsoLinks(t *testing.T) {
+	root := testSubCommand().Parent()
+
+	var md bytes.Buffer
+	if err := generateMarkdownPage(root, &GenerateMarkdownOptions{}, &md); err != nil {
+		t.Fatalf("generateMarkdownPage: %v", err)
+	}
+	if !strings.Contains(md.String(), "[app sub](app-sub.md)") {
+		t.Errorf("markdown missing SEE ALSO link: %s", md.String())
+	}
+
+	var rst bytes.Buffer
+	if err := generateReSTPage(root, &GenerateReSTOptions{}, &rst); err != nil {
+		t.Fatalf("generateReSTPage: %v", err)
+	}
+	if !strings.Contains(rst.String(), "app sub") {
+		t.Errorf("reST missing SEE ALSO entry: %s", rst.String())
+	}
+}
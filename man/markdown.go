@@ -0,0 +1,192 @@
+// Copyright 2015 Red Hat Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package man
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/spf13/cobra"
+)
+
+// GenerateMarkdownOptions is used to configure how GenerateMarkdownDocs
+// will do its job.
+type GenerateMarkdownOptions struct {
+	// Author if set will create a Author section with this content.
+	Author string
+
+	// Files if set with content will create a FILES section for all
+	// pages.  If you want this section only for a single command add
+	// it as an annotation: cmd.Annotations["man-files-section"]
+	Files string
+
+	// Bugs if set with content will create a BUGS section for all
+	// pages.  If you want this section only for a single command add
+	// it as an annotation: cmd.Annotations["man-bugs-section"]
+	Bugs string
+
+	// Environment if set with content will create a ENVIRONMENT section for all
+	// pages.  If you want this section only for a single command add
+	// it as an annotation: cmd.Annotations["man-environment-section"]
+	Environment string
+
+	// Directory location for where to generate the markdown docs
+	Directory string
+
+	// CommandSeparator defines what character to use to separate the
+	// sub commands in the doc file name.  The '-' char is the default.
+	CommandSeparator string
+
+	// UseTemplate allows you to override the default go template used to
+	// generate the markdown docs with your own version.
+	UseTemplate string
+
+	// CustomSections declares extra sections beyond the built-in
+	// FILES/BUGS/ENVIRONMENT/EXAMPLES ones. See CustomSection for how a
+	// command overrides one for itself.
+	CustomSections []CustomSection
+
+	// FilePrepender is called for each generated file and allows you to
+	// inject content (e.g. Hugo or Jekyll frontmatter) before the
+	// generated documentation.
+	FilePrepender func(filename string) string
+
+	// LinkHandler is called for every cross reference (parent, sibling or
+	// child command) found while generating a page and allows you to
+	// rewrite the href that command links to from the generated markdown.
+	// It receives the command path (e.g. "app sub") and the default
+	// reference filename (e.g. "app-sub.md") and returns the href to use;
+	// the link text itself is always the command path. When nil, the SEE
+	// ALSO section links to the default reference filename.
+	LinkHandler func(cmdPath, ref string) string
+}
+
+// GenerateMarkdownDocs - build markdown docs for the passed in cobra.Command
+// and all of its children
+func GenerateMarkdownDocs(cmd *cobra.Command, opts *GenerateMarkdownOptions) error {
+	for _, c := range cmd.Commands() {
+		if !c.IsAvailableCommand() || c.IsAdditionalHelpTopicCommand() {
+			continue
+		}
+		if err := GenerateMarkdownDocs(c, opts); err != nil {
+			return err
+		}
+	}
+
+	separator := "-"
+	if opts.CommandSeparator != "" {
+		separator = opts.CommandSeparator
+	}
+	basename := strings.Replace(cmd.CommandPath(), " ", separator, -1)
+	if basename == "" {
+		return fmt.Errorf("you need a command name to have a markdown doc")
+	}
+	filename := filepath.Join(opts.Directory, basename+".md")
+	return createAtomic(filename, func(f io.Writer) error {
+		if opts.FilePrepender != nil {
+			if _, err := io.WriteString(f, opts.FilePrepender(filename)); err != nil {
+				return err
+			}
+		}
+		return generateMarkdownPage(cmd, opts, f)
+	})
+}
+
+func generateMarkdownPage(cmd *cobra.Command, opts *GenerateMarkdownOptions, w io.Writer) error {
+	values := newDocStruct(cmd, "", opts.Author, opts.Files, opts.Bugs, opts.Environment, opts.CustomSections)
+
+	separator := "-"
+	if opts.CommandSeparator != "" {
+		separator = opts.CommandSeparator
+	}
+
+	markdownTemplateStr := defaultMarkdownTemplate
+	if opts.UseTemplate != "" {
+		markdownTemplateStr = opts.UseTemplate
+	}
+	funcMap := template.FuncMap{
+		"upper": strings.ToUpper,
+		"seeAlsoLink": func(cmdPath string) string {
+			ref := strings.Replace(cmdPath, " ", separator, -1) + ".md"
+			href := ref
+			if opts.LinkHandler != nil {
+				href = opts.LinkHandler(cmdPath, ref)
+			}
+			return "[" + cmdPath + "](" + href + ")"
+		},
+	}
+	parsedTemplate, err := template.New("markdown").Funcs(funcMap).Parse(markdownTemplateStr)
+	if err != nil {
+		return err
+	}
+	return parsedTemplate.Execute(w, values)
+}
+
+const defaultMarkdownTemplate = `## {{.CommandPath}}
+
+{{.ShortDescription}}
+
+### Synopsis
+
+{{.Description}}
+
+` + "```" + `
+{{.UseLine}}
+` + "```" + `
+{{if .NonInheritedFlags}}
+### Options
+
+` + "```" + `
+{{range .NonInheritedFlags}}  {{if .Shorthand}}-{{.Shorthand}}, {{end}}--{{.Name}}{{if .ArgHint}} {{.ArgHint}}{{end}}   {{.Usage}}
+{{end}}` + "```" + `
+{{end}}{{if .InheritedFlags}}
+### Options inherited from parent commands
+
+` + "```" + `
+{{range .InheritedFlags}}  {{if .Shorthand}}-{{.Shorthand}}, {{end}}--{{.Name}}{{if .ArgHint}} {{.ArgHint}}{{end}}   {{.Usage}}
+{{end}}` + "```" + `
+{{end}}{{if .Examples}}
+### Examples
+
+` + "```" + `
+{{.Examples}}
+` + "```" + `
+{{end}}{{if .Environment}}
+### Environment
+
+{{.Environment}}
+{{end}}{{if .Files}}
+### Files
+
+{{.Files}}
+{{end}}{{if .Bugs}}
+### Bugs
+
+{{.Bugs}}
+{{end}}{{range .CustomSections}}
+### {{.Heading}}
+
+{{.Body}}
+{{end}}{{if .SeeAlsos}}
+### See Also
+
+{{range .SeeAlsos}}* {{seeAlsoLink .CmdPath}}
+{{end}}{{end}}{{if .Author}}
+### Author
+
+{{.Author}}
+{{end}}`
@@ -0,0 +1,133 @@
+// Copyright 2015 Red Hat Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package man
+
+import (
+	"io"
+	"strings"
+	"text/template"
+)
+
+// Renderer turns an extracted Model into a specific man page dialect.
+// GenerateManOptions.Renderer lets callers plug in an alternate dialect (or
+// their own) without touching the extraction logic in BuildModel.
+type Renderer interface {
+	Render(w io.Writer, values Model) error
+}
+
+// troffRenderer is the default Renderer: it emits classic troff/man(7)
+// pages and is what GenerateManPages has always produced.
+type troffRenderer struct {
+	// UseTemplate overrides the default troff template.
+	UseTemplate string
+}
+
+func (r *troffRenderer) Render(w io.Writer, values Model) error {
+	return RenderModel(&values, w, r.UseTemplate)
+}
+
+// RenderModel renders m as a troff/man(7) page using tmpl (defaultManTemplate
+// when tmpl is empty), writing the result to w. It is the primitive behind
+// both GenerateManPages and troffRenderer, exposed so callers who built or
+// transformed a Model themselves can render it without going through a
+// cobra.Command again.
+func RenderModel(m *Model, w io.Writer, tmpl string) error {
+	manTemplateStr := defaultManTemplate
+	if tmpl != "" {
+		manTemplateStr = tmpl
+	}
+	funcMap := template.FuncMap{
+		"upper":         strings.ToUpper,
+		"backslashify":  backslashify,
+		"dashify":       dashify,
+		"simpleToTroff": simpleToTroff,
+		"simpleToMdoc":  simpleToMdoc,
+	}
+	parsedTemplate, err := template.New("man").Funcs(funcMap).Parse(manTemplateStr)
+	if err != nil {
+		return err
+	}
+	return parsedTemplate.Execute(w, m)
+}
+
+// MdocRenderer renders man pages using the BSD mdoc(7) macro set instead
+// of troff/man(7). Set GenerateManOptions.Renderer to &MdocRenderer{} to
+// use it:
+//
+//	opts := &man.GenerateManOptions{Renderer: &man.MdocRenderer{}}
+type MdocRenderer struct {
+	// UseTemplate overrides the default mdoc(7) template.
+	UseTemplate string
+}
+
+func (r *MdocRenderer) Render(w io.Writer, values Model) error {
+	mdocTemplateStr := defaultMdocTemplate
+	if r.UseTemplate != "" {
+		mdocTemplateStr = r.UseTemplate
+	}
+	funcMap := template.FuncMap{
+		"upper":        strings.ToUpper,
+		"dashify":      dashify,
+		"simpleToMdoc": simpleToMdoc,
+	}
+	parsedTemplate, err := template.New("mdoc").Funcs(funcMap).Parse(mdocTemplateStr)
+	if err != nil {
+		return err
+	}
+	return parsedTemplate.Execute(w, values)
+}
+
+// defaultMdocTemplate wraps flag descriptions in a tagged list (.Bl
+// -tag/.El) rather than the backslash-escaped runs troff needs; `code`
+// spans in prose become .Sy lines instead of \fB escapes. Prose lines are
+// otherwise emitted as given: they are not split into one sentence per
+// line, and no .Em emphasis is produced.
+const defaultMdocTemplate = `.Dd {{.Date.Format "January 2, 2006"}}
+.Dt {{dashify .CommandPath | upper}} {{.Section}}
+.Os
+.Sh NAME
+.Nm {{dashify .CommandPath}}
+.Nd {{.ShortDescription}}
+.Sh SYNOPSIS
+.Nm {{dashify .CommandPath}}
+{{range .NonInheritedFlags}}.Op Fl {{if .Shorthand}}{{.Shorthand}} | Fl {{end}}{{.Name}}{{if .ArgHint}} Ar {{.ArgHint}}{{end}}
+{{end}}{{if not .NoArgs}}.Op Ar
+{{end}}.Sh DESCRIPTION
+{{simpleToMdoc .Description}}
+{{if .NonInheritedFlags}}.Sh OPTIONS
+.Bl -tag -width Ds
+{{range .NonInheritedFlags}}.It Fl {{if .Shorthand}}{{.Shorthand}} , Fl {{end}}{{.Name}}{{if .ArgHint}} Ar {{.ArgHint}}{{end}}
+{{.Usage}}
+{{end}}.El
+{{end}}{{if .InheritedFlags}}.Sh OPTIONS INHERITED FROM PARENT COMMANDS
+.Bl -tag -width Ds
+{{range .InheritedFlags}}.It Fl {{if .Shorthand}}{{.Shorthand}} , Fl {{end}}{{.Name}}{{if .ArgHint}} Ar {{.ArgHint}}{{end}}
+{{.Usage}}
+{{end}}.El
+{{end}}{{if .Examples}}.Sh EXAMPLES
+{{simpleToMdoc .Examples}}
+{{end}}{{if .Environment}}.Sh ENVIRONMENT
+{{simpleToMdoc .Environment}}
+{{end}}{{if .Files}}.Sh FILES
+{{simpleToMdoc .Files}}
+{{end}}{{if .Bugs}}.Sh BUGS
+{{simpleToMdoc .Bugs}}
+{{end}}{{range .CustomSections}}.Sh {{upper .Heading}}
+{{simpleToMdoc .Body}}
+{{end}}{{if .SeeAlsos}}.Sh SEE ALSO
+{{range $i, $see := .SeeAlsos}}{{if $i}},
+{{end}}.Xr {{dashify $see.CmdPath}} {{$see.Section}}{{end}}
+{{end}}{{if .Author}}.Sh AUTHORS
+{{simpleToMdoc .Author}}
+{{end}}`
@@ -0,0 +1,49 @@
+// Copyright 2015 Red Hat Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package man
+
+// defaultManTemplate is the troff/man(7) template used to render a man
+// page from a GenerateManOptions-populated data set. CustomSections are
+// rendered after BUGS and before SEE ALSO, matching the mdoc template.
+const defaultManTemplate = `.TH "{{dashify .CommandPath | upper}}" "{{.Section}}" "{{.CenterFooter}}" "{{.LeftFooter}}" "{{.CenterHeader}}"
+.SH NAME
+{{dashify .CommandPath}} \- {{.ShortDescription}}
+.SH SYNOPSIS
+\fB{{backslashify .UseLine}}\fR
+.SH DESCRIPTION
+{{simpleToTroff .Description}}
+{{if .NonInheritedFlags}}.SH OPTIONS
+{{range .NonInheritedFlags}}.TP
+\fB{{if .Shorthand}}\-{{.Shorthand}}, {{end}}\-\-{{.Name}}\fR{{if .ArgHint}} {{.ArgHint}}{{end}}
+{{backslashify .Usage}}
+{{end}}{{end}}{{if .InheritedFlags}}.SH OPTIONS INHERITED FROM PARENT COMMANDS
+{{range .InheritedFlags}}.TP
+\fB{{if .Shorthand}}\-{{.Shorthand}}, {{end}}\-\-{{.Name}}\fR{{if .ArgHint}} {{.ArgHint}}{{end}}
+{{backslashify .Usage}}
+{{end}}{{end}}{{if .Examples}}.SH EXAMPLES
+{{simpleToTroff .Examples}}
+{{end}}{{if .Environment}}.SH ENVIRONMENT
+{{simpleToTroff .Environment}}
+{{end}}{{if .Files}}.SH FILES
+{{simpleToTroff .Files}}
+{{end}}{{if .Bugs}}.SH BUGS
+{{simpleToTroff .Bugs}}
+{{end}}{{range .CustomSections}}.SH {{upper .Heading}}
+{{simpleToTroff .Body}}
+{{end}}{{if .SeeAlsos}}.SH SEE ALSO
+{{range $i, $see := .SeeAlsos}}{{if $i}},
+{{end}}\fB{{dashify $see.CmdPath}}\fR({{$see.Section}}){{end}}
+{{end}}{{if .Author}}.SH AUTHOR
+{{simpleToTroff .Author}}
+{{end}}`
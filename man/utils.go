@@ -0,0 +1,119 @@
+// Copyright 2015 Red Hat Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package man
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var inlineCodeRE = regexp.MustCompile("`([^`]+)`")
+
+// dashify replaces the spaces in a command path ("myapp sub") with the
+// separator man page names conventionally use ("myapp-sub").
+func dashify(s string) string {
+	return strings.Replace(s, " ", "-", -1)
+}
+
+// backslashify escapes the characters troff treats specially in running
+// text: backslashes (so literal backslashes in flag usage strings don't
+// get interpreted as escape sequences) and hyphens (so troff doesn't
+// treat them as hyphenation points when reflowing).
+func backslashify(s string) string {
+	s = strings.Replace(s, `\`, `\e`, -1)
+	s = strings.Replace(s, "-", `\-`, -1)
+	return s
+}
+
+// simpleToTroff renders free-form prose (an opts.Files/Bugs/Environment/
+// Examples/Author value) as troff. A value that already starts with '.'
+// is assumed to be valid troff and is passed through untouched, matching
+// the "if it starts with a '.' we assume it is valid troff" contract
+// documented on GenerateManOptions. Anything else has its troff-special
+// characters escaped, blank lines turned into paragraph breaks, and
+// `code` spans turned into \fBcode\fR.
+func simpleToTroff(s string) string {
+	if strings.HasPrefix(strings.TrimSpace(s), ".") {
+		return s
+	}
+
+	lines := strings.Split(s, "\n")
+	out := make([]string, 0, len(lines))
+	for _, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			out = append(out, ".PP")
+			continue
+		}
+		line = backslashify(line)
+		line = inlineCodeRE.ReplaceAllString(line, `\fB$1\fR`)
+		out = append(out, line)
+	}
+	return strings.Join(out, "\n")
+}
+
+// simpleToMdoc is simpleToTroff's mdoc(7) counterpart: the same
+// "starts with '.' means pass it through" rule applies, but mdoc only
+// recognizes a macro as the first word of its own line, so `code` spans
+// can't be rewritten to `.Sy code` in place the way troff's \fB...\fR
+// escape works mid-line. Instead each span is split onto its own .Sy
+// line, with the surrounding text kept on lines of its own.
+func simpleToMdoc(s string) string {
+	if strings.HasPrefix(strings.TrimSpace(s), ".") {
+		return s
+	}
+
+	lines := strings.Split(s, "\n")
+	out := make([]string, 0, len(lines))
+	for _, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			out = append(out, ".Pp")
+			continue
+		}
+		out = append(out, mdocInlineCode(line)...)
+	}
+	return strings.Join(out, "\n")
+}
+
+// mdocInlineCode splits a line of prose on `code` spans, emitting the
+// surrounding text verbatim and each span as its own ".Sy" line.
+func mdocInlineCode(line string) []string {
+	matches := inlineCodeRE.FindAllStringSubmatchIndex(line, -1)
+	if matches == nil {
+		return []string{line}
+	}
+
+	var out []string
+	pos := 0
+	for _, m := range matches {
+		if before := strings.TrimSpace(line[pos:m[0]]); before != "" {
+			out = append(out, before)
+		}
+		out = append(out, ".Sy "+line[m[2]:m[3]])
+		pos = m[1]
+	}
+	if after := strings.TrimSpace(line[pos:]); after != "" {
+		out = append(out, after)
+	}
+	return out
+}
+
+// byName sorts a slice of *cobra.Command by Name(), giving generated pages
+// a stable order wherever sibling or child commands are listed.
+type byName []*cobra.Command
+
+func (b byName) Len() int           { return len(b) }
+func (b byName) Swap(i, j int)      { b[i], b[j] = b[j], b[i] }
+func (b byName) Less(i, j int) bool { return b[i].Name() < b[j].Name() }
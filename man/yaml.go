@@ -0,0 +1,171 @@
+// Copyright 2015 Red Hat Inc. All rights reserved.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+// http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package man
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v2"
+)
+
+// GenerateYAMLOptions is used to configure how GenerateYAMLDocs will do
+// its job.
+type GenerateYAMLOptions struct {
+	// Author if set will create a Author section with this content.
+	Author string
+
+	// Files if set with content will create a FILES section for all
+	// pages.  If you want this section only for a single command add
+	// it as an annotation: cmd.Annotations["man-files-section"]
+	Files string
+
+	// Bugs if set with content will create a BUGS section for all
+	// pages.  If you want this section only for a single command add
+	// it as an annotation: cmd.Annotations["man-bugs-section"]
+	Bugs string
+
+	// Environment if set with content will create a ENVIRONMENT section for all
+	// pages.  If you want this section only for a single command add
+	// it as an annotation: cmd.Annotations["man-environment-section"]
+	Environment string
+
+	// Directory location for where to generate the YAML docs
+	Directory string
+
+	// CommandSeparator defines what character to use to separate the
+	// sub commands in the doc file name.  The '-' char is the default.
+	CommandSeparator string
+
+	// FilePrepender is called for each generated file and allows you to
+	// inject content (e.g. Hugo or Jekyll frontmatter) before the
+	// generated documentation.
+	FilePrepender func(filename string) string
+
+	// LinkHandler is called for every cross reference (parent, sibling or
+	// child command) found while generating a page and allows you to
+	// rewrite how that command is linked to from the generated YAML. It
+	// receives the command path (e.g. "app sub") and the default
+	// reference filename (e.g. "app-sub.yaml") and returns the string to
+	// record for that SEE ALSO entry.
+	LinkHandler func(cmdPath, ref string) string
+
+	// CustomSections declares extra sections beyond the built-in
+	// FILES/BUGS/ENVIRONMENT/EXAMPLES ones. See CustomSection for how a
+	// command overrides one for itself.
+	CustomSections []CustomSection
+}
+
+// yamlDoc mirrors Model but is trimmed to the fields that make sense
+// once rendered as data rather than through a text/template, and carries
+// yaml tags so the emitted keys are stable and lower case.
+type yamlDoc struct {
+	CommandPath      string        `yaml:"name"`
+	ShortDescription string        `yaml:"synopsis"`
+	Description      string        `yaml:"description"`
+	UseLine          string        `yaml:"usage"`
+	AllFlags         []Flag        `yaml:"options,omitempty"`
+	InheritedFlags   []Flag        `yaml:"inherited_options,omitempty"`
+	SeeAlsos         []seeAlsoYAML `yaml:"see_also,omitempty"`
+	Examples         string        `yaml:"examples,omitempty"`
+	Environment      string        `yaml:"environment,omitempty"`
+	Files            string        `yaml:"files,omitempty"`
+	Bugs             string        `yaml:"bugs,omitempty"`
+	Author           string        `yaml:"author,omitempty"`
+
+	CustomSections []CustomSectionValue `yaml:"custom_sections,omitempty"`
+}
+
+// seeAlsoYAML mirrors SeeAlso but keeps LinkHandler's rewritten reference
+// in its own Href field rather than overwriting CmdPath, so the command
+// name stays readable even once a LinkHandler is in use.
+type seeAlsoYAML struct {
+	CmdPath string `yaml:"cmd_path"`
+	Section string `yaml:"section,omitempty"`
+	Href    string `yaml:"href,omitempty"`
+}
+
+// GenerateYAMLDocs - build YAML docs for the passed in cobra.Command and
+// all of its children
+func GenerateYAMLDocs(cmd *cobra.Command, opts *GenerateYAMLOptions) error {
+	for _, c := range cmd.Commands() {
+		if !c.IsAvailableCommand() || c.IsAdditionalHelpTopicCommand() {
+			continue
+		}
+		if err := GenerateYAMLDocs(c, opts); err != nil {
+			return err
+		}
+	}
+
+	separator := "-"
+	if opts.CommandSeparator != "" {
+		separator = opts.CommandSeparator
+	}
+	basename := strings.Replace(cmd.CommandPath(), " ", separator, -1)
+	if basename == "" {
+		return fmt.Errorf("you need a command name to have a YAML doc")
+	}
+	filename := filepath.Join(opts.Directory, basename+".yaml")
+	return createAtomic(filename, func(f io.Writer) error {
+		if opts.FilePrepender != nil {
+			if _, err := io.WriteString(f, opts.FilePrepender(filename)); err != nil {
+				return err
+			}
+		}
+		return generateYAMLPage(cmd, opts, f)
+	})
+}
+
+func generateYAMLPage(cmd *cobra.Command, opts *GenerateYAMLOptions, w io.Writer) error {
+	values := newDocStruct(cmd, "", opts.Author, opts.Files, opts.Bugs, opts.Environment, opts.CustomSections)
+
+	separator := "-"
+	if opts.CommandSeparator != "" {
+		separator = opts.CommandSeparator
+	}
+	seeAlsos := make([]seeAlsoYAML, len(values.SeeAlsos))
+	for i, see := range values.SeeAlsos {
+		seeAlsos[i] = seeAlsoYAML{CmdPath: see.CmdPath, Section: see.Section}
+		if opts.LinkHandler != nil {
+			ref := strings.Replace(see.CmdPath, " ", separator, -1) + ".yaml"
+			seeAlsos[i].Href = opts.LinkHandler(see.CmdPath, ref)
+		}
+	}
+
+	doc := yamlDoc{
+		CommandPath:      values.CommandPath,
+		ShortDescription: values.ShortDescription,
+		Description:      values.Description,
+		UseLine:          values.UseLine,
+		AllFlags:         values.AllFlags,
+		InheritedFlags:   values.InheritedFlags,
+		SeeAlsos:         seeAlsos,
+		Examples:         values.Examples,
+		Environment:      values.Environment,
+		Files:            values.Files,
+		Bugs:             values.Bugs,
+		Author:           values.Author,
+		CustomSections:   values.CustomSections,
+	}
+
+	out, err := yaml.Marshal(doc)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(out)
+	return err
+}